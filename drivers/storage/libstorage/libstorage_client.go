@@ -1,12 +1,22 @@
 package libstorage
 
 import (
+	"crypto"
+	"crypto/ed25519"
 	"crypto/md5"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math/rand"
 	"os"
 	"path"
+	"sync"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 
@@ -19,6 +29,81 @@ import (
 	"github.com/codedellemc/libstorage/api/utils"
 )
 
+const (
+	// configDialRetryMax is the maximum number of retries made after the
+	// initial Services call fails before dial gives up and returns an error,
+	// so at most this value plus one attempts are made. Zero (the default)
+	// disables retrying.
+	configDialRetryMax = "libstorage.client.dial.retry.max"
+
+	// configDialRetryInterval is the base interval, in milliseconds,
+	// between retries of the initial Services call. The actual delay is
+	// this value backed off exponentially per attempt plus jitter.
+	configDialRetryInterval = "libstorage.client.dial.retry.interval"
+
+	// configDialRefreshInterval is the interval, in seconds, at which the
+	// background refresher re-attempts cache initialization for services
+	// that were skipped during dial. A value of zero disables the
+	// refresher.
+	configDialRefreshInterval = "libstorage.client.dial.refreshInterval"
+
+	// configLSXCacheDir is the directory under which the content-addressed
+	// executor cache is stored. An empty value (the default) disables the
+	// cache entirely, and updateExecutor falls back to the single
+	// cross-process mutex used before this feature existed.
+	configLSXCacheDir = "libstorage.client.lsxCache.dir"
+
+	// configLSXCacheGCInterval is the interval, in seconds, at which the
+	// content-addressed executor cache is swept for unreferenced entries
+	// older than configLSXCacheGCTTL. A value of zero (the default)
+	// disables garbage collection.
+	configLSXCacheGCInterval = "libstorage.client.lsxCache.gc.interval"
+
+	// configLSXCacheGCTTL is how old, in seconds, an unreferenced executor
+	// cache entry must be before it is eligible for garbage collection.
+	configLSXCacheGCTTL = "libstorage.client.lsxCache.gc.ttl"
+
+	// configLSXSignaturePath is the path to the detached signature file
+	// covering the configured executor. An empty value (the default)
+	// disables signature verification.
+	configLSXSignaturePath = "libstorage.client.executor.signaturePath"
+
+	// configLSXTrustAnchor is the path to the PEM-encoded public key used
+	// to verify configLSXSignaturePath. An empty value (the default)
+	// disables signature verification.
+	configLSXTrustAnchor = "libstorage.client.executor.trustAnchor"
+)
+
+// defaultLSXCacheGCTTL is used when garbage collection is enabled via
+// configLSXCacheGCInterval but configLSXCacheGCTTL is unset.
+const defaultLSXCacheGCTTL = 7 * 24 * time.Hour
+
+// ServiceStatus describes the health of a single service's client-side
+// caches, as reported by ClientStatus.
+type ServiceStatus string
+
+const (
+	// ServiceStatusReady indicates the service's supported and instance ID
+	// caches initialized successfully.
+	ServiceStatusReady ServiceStatus = "Ready"
+
+	// ServiceStatusDegraded indicates the service's supported cache
+	// initialized but its driver does not implement instance ID lookup
+	// (types.ErrNotImplemented). Any other instance ID error yields
+	// ServiceStatusUnavailable.
+	ServiceStatusDegraded ServiceStatus = "Degraded"
+
+	// ServiceStatusUnavailable indicates the service's caches failed to
+	// initialize and the service is not usable until the next refresh
+	// succeeds.
+	ServiceStatusUnavailable ServiceStatus = "Unavailable"
+
+	// ServiceStatusNotApplicable indicates the service's platform has no
+	// executor to support, which is expected for many services and is not
+	// a failure condition.
+	ServiceStatusNotApplicable ServiceStatus = "NotApplicable"
+)
+
 type client struct {
 	types.APIClient
 	ctx             types.Context
@@ -31,17 +116,27 @@ type client struct {
 	supportedCache  *lss
 	instanceIDCache types.Store
 	lsxMutexPath    string
+
+	serviceStatusRWL sync.RWMutex
+	serviceStatus    map[string]ServiceStatus
 }
 
+// errExecutorNotSupported is not returned by any client method in this
+// package. When a platform has no executor, initServiceCaches records
+// ServiceStatusNotApplicable rather than returning an error.
 var errExecutorNotSupported = errors.New("executor not supported")
 
 func (c *client) isController() bool {
 	return c.clientType == types.ControllerClient
 }
 
+func (c *client) lsxCacheDir() string {
+	return c.config.GetString(configLSXCacheDir)
+}
+
 func (c *client) dial(ctx types.Context) error {
 
-	svcInfos, err := c.Services(ctx)
+	svcInfos, err := c.dialServices(ctx)
 	if err != nil {
 		return err
 	}
@@ -66,30 +161,168 @@ func (c *client) dial(ctx types.Context) error {
 		}
 	}
 
+	var skipped []string
 	for service := range svcInfos {
-		ctx := c.ctx.WithValue(context.ServiceKey, service)
-		ctx.Info("initializing supported cache")
-		lsxSO, err := c.Supported(ctx, store)
-		if err != nil {
-			return goof.WithError("error initializing supported cache", err)
+		if c.initServiceCaches(ctx, store, service) != nil {
+			skipped = append(skipped, service)
+		}
+	}
+
+	if interval := c.config.GetInt(configDialRefreshInterval); interval > 0 {
+		go c.refreshServiceCaches(store, time.Duration(interval)*time.Second)
+	} else if len(skipped) > 0 {
+		ctx.WithField("services", skipped).Warn(
+			"some services are degraded and no refreshInterval is configured")
+	}
+
+	if c.lsxCacheDir() != "" {
+		if gcInterval := c.config.GetInt(configLSXCacheGCInterval); gcInterval > 0 {
+			ttl := time.Duration(
+				c.config.GetInt(configLSXCacheGCTTL)) * time.Second
+			if ttl <= 0 {
+				ttl = defaultLSXCacheGCTTL
+			}
+			go c.gcExecutorCacheLoop(
+				time.Duration(gcInterval)*time.Second, ttl)
+		}
+	}
+
+	return nil
+}
+
+// dialServices calls Services with retry, exponential backoff, and jitter so
+// that a client started against a temporarily unavailable libStorage server
+// does not fail hard.
+func (c *client) dialServices(
+	ctx types.Context) (map[string]*types.ServiceInfo, error) {
+
+	maxRetries := c.config.GetInt(configDialRetryMax)
+	baseInterval := time.Duration(c.config.GetInt(configDialRetryInterval)) *
+		time.Millisecond
+	if baseInterval <= 0 {
+		baseInterval = 500 * time.Millisecond
+	}
+
+	var (
+		svcInfos map[string]*types.ServiceInfo
+		err      error
+	)
+
+	for attempt := 0; ; attempt++ {
+		svcInfos, err = c.Services(ctx)
+		if err == nil {
+			return svcInfos, nil
+		}
+
+		if attempt >= maxRetries {
+			return nil, goof.WithFieldE(
+				"attempts", attempt+1, "error dialing libStorage server", err)
 		}
 
-		if lsxSO == types.LSXSOpNone {
-			ctx.Warn("executor not supported")
+		delay := backoffWithJitter(baseInterval, attempt)
+		ctx.WithFields(log.Fields{
+			"attempt": attempt + 1,
+			"delay":   delay,
+		}).WithError(err).Warn("error dialing libStorage server, retrying")
+		time.Sleep(delay)
+	}
+}
+
+// backoffWithJitter returns base backed off exponentially by attempt, with
+// up to 50% random jitter added to avoid a thundering herd of clients
+// retrying in lockstep.
+func backoffWithJitter(base time.Duration, attempt int) time.Duration {
+	backoff := base * time.Duration(1<<uint(attempt))
+	jitter := time.Duration(rand.Int63n(int64(backoff)/2 + 1))
+	return backoff + jitter
+}
+
+// initServiceCaches initializes the supported and instance ID caches for a
+// single service, isolated from every other service so that one service's
+// failure does not abort initialization of its siblings. The resulting
+// status is recorded and retrievable via ClientStatus.
+func (c *client) initServiceCaches(
+	ctx types.Context, store types.Store, service string) error {
+
+	svcCtx := c.ctx.WithValue(context.ServiceKey, service)
+	svcCtx.Info("initializing supported cache")
+
+	lsxSO, err := c.Supported(svcCtx, store)
+	if err != nil {
+		c.setServiceStatus(service, ServiceStatusUnavailable)
+		svcCtx.WithError(err).Warn("error initializing supported cache")
+		return err
+	}
+
+	if lsxSO == types.LSXSOpNone {
+		svcCtx.Warn("executor not supported")
+		c.setServiceStatus(service, ServiceStatusNotApplicable)
+		return nil
+	}
+
+	svcCtx.Info("initializing instance ID cache")
+	if _, err := c.InstanceID(svcCtx, store); err != nil {
+		if err == types.ErrNotImplemented {
+			svcCtx.WithError(err).Warn("cannot get instance ID")
+			c.setServiceStatus(service, ServiceStatusDegraded)
+			return nil
+		}
+		c.setServiceStatus(service, ServiceStatusUnavailable)
+		svcCtx.WithError(err).Warn("error initializing instance ID cache")
+		return err
+	}
+
+	c.setServiceStatus(service, ServiceStatusReady)
+	return nil
+}
+
+// refreshServiceCaches periodically re-runs initServiceCaches for every
+// service that is not currently Ready or NotApplicable, allowing a client
+// that started in degraded mode to recover once the backing service
+// becomes available. NotApplicable services are never retried: their
+// platform has no executor to support, and that cannot change at runtime.
+func (c *client) refreshServiceCaches(
+	store types.Store, interval time.Duration) {
+
+	for range time.Tick(interval) {
+		svcInfos, err := c.Services(c.ctx)
+		if err != nil {
+			c.ctx.WithError(err).Warn("error refreshing services")
 			continue
 		}
 
-		ctx.Info("initializing instance ID cache")
-		if _, err := c.InstanceID(ctx, store); err != nil {
-			if err == types.ErrNotImplemented {
-				ctx.WithError(err).Warn("cannot get instance ID")
+		for service := range svcInfos {
+			switch c.ClientStatus()[service] {
+			case ServiceStatusReady, ServiceStatusNotApplicable:
 				continue
 			}
-			return goof.WithError("error initializing instance ID cache", err)
+			c.initServiceCaches(c.ctx, store, service)
 		}
 	}
+}
 
-	return nil
+// ClientStatus reports the last known health of every service's
+// client-side caches, allowing a caller to start in degraded mode rather
+// than crash-looping when one or more services are unavailable.
+func (c *client) ClientStatus() map[string]ServiceStatus {
+	c.serviceStatusRWL.RLock()
+	defer c.serviceStatusRWL.RUnlock()
+
+	status := make(map[string]ServiceStatus, len(c.serviceStatus))
+	for k, v := range c.serviceStatus {
+		status[k] = v
+	}
+	return status
+}
+
+func (c *client) setServiceStatus(service string, status ServiceStatus) {
+	c.serviceStatusRWL.Lock()
+	defer c.serviceStatusRWL.Unlock()
+
+	if c.serviceStatus == nil {
+		c.serviceStatus = map[string]ServiceStatus{}
+	}
+	c.serviceStatus[service] = status
 }
 
 func getHost(
@@ -133,6 +366,21 @@ func (c *client) updateExecutor(ctx types.Context) error {
 		return goof.WithField("lsx", c.pathConfig.LSX, "unknown executor")
 	}
 
+	checksum := executorChecksum(lsxi)
+	if c.lsxCacheDir() == "" || checksum == "" {
+		return c.updateExecutorLegacy(ctx, lsxi)
+	}
+
+	return c.updateExecutorFromCache(ctx, checksum)
+}
+
+// updateExecutorLegacy is the pre-content-addressed-cache update path, used
+// when configLSXCacheDir is not configured or the server has not
+// advertised a checksum for the executor. It serializes through a single
+// cross-process mutex and overwrites PathConfig.LSX directly.
+func (c *client) updateExecutorLegacy(
+	ctx types.Context, lsxi *types.ExecutorInfo) error {
+
 	ctx.Debug("waiting on executor lock")
 	if err := c.lsxMutexWait(); err != nil {
 		return err
@@ -151,57 +399,164 @@ func (c *client) updateExecutor(ctx types.Context) error {
 
 	ctx.Debug("executor exists, getting local checksum")
 
-	checksum, err := c.getExecutorChecksum(ctx)
+	f, err := os.Open(c.pathConfig.LSX)
+	if err != nil {
+		return err
+	}
+	checksum, err := hashReader(f)
+	f.Close()
 	if err != nil {
 		return err
 	}
 
-	if lsxi.MD5Checksum != checksum {
+	if executorChecksum(lsxi) != checksum {
 		ctx.WithFields(log.Fields{
-			"remoteChecksum": lsxi.MD5Checksum,
+			"remoteChecksum": executorChecksum(lsxi),
 			"localChecksum":  checksum,
 		}).Debug("executor checksums do not match, download executor")
 		return c.downloadExecutor(ctx)
 	}
 
-	return nil
+	return c.verifyExecutorSignature(ctx, c.pathConfig.LSX)
 }
 
-func (c *client) getExecutorChecksum(ctx types.Context) (string, error) {
+// updateExecutorFromCache links PathConfig.LSX to the content-addressed
+// cache entry matching checksum, downloading into the cache first if no
+// client on this host has fetched that checksum yet. A cross-process lock
+// keyed by checksum lets concurrent clients on the same host cooperate
+// instead of serializing through a single, executor-wide mutex.
+func (c *client) updateExecutorFromCache(
+	ctx types.Context, checksum string) error {
+
+	entryPath := c.lsxCacheEntryPath(checksum)
+	if gotil.FileExists(entryPath) {
+		ctx.WithField("checksum", checksum).Debug(
+			"executor already cached, skipping download")
+		if err := c.verifyExecutorSignature(ctx, entryPath); err != nil {
+			return err
+		}
+		return c.linkExecutor(ctx, entryPath)
+	}
 
-	if c.isController() {
-		return "", utils.NewUnsupportedForClientTypeError(
-			c.clientType, "getExecutorChecksum")
+	ctx.WithField("checksum", checksum).Debug("waiting on executor cache lock")
+	unlock, err := c.lockCacheEntry(ctx, checksum)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			ctx.WithError(err).Warn("error releasing executor cache lock")
+		}
+	}()
+
+	if gotil.FileExists(entryPath) {
+		// another client populated the cache while this one waited on the
+		// lock; nothing left to do but verify its signature and link to it.
+		if err := c.verifyExecutorSignature(ctx, entryPath); err != nil {
+			return err
+		}
+		return c.linkExecutor(ctx, entryPath)
 	}
 
-	ctx.Debug("getting executor checksum")
+	if err := c.downloadExecutor(ctx); err != nil {
+		return err
+	}
 
-	f, err := os.Open(c.pathConfig.LSX)
-	if err != nil {
+	return c.linkExecutor(ctx, entryPath)
+}
+
+// executorChecksum returns the MD5 checksum the server advertised for the
+// executor. MD5 is the only integrity signal ExecutorInfo carries; a
+// future libStorage release may add a negotiated digest algorithm, at
+// which point this should prefer it.
+func executorChecksum(lsxi *types.ExecutorInfo) string {
+	return lsxi.MD5Checksum
+}
+
+// hashReader returns the hex-encoded MD5 digest of r, matching the
+// checksum algorithm ExecutorInfo.MD5Checksum advertises.
+func hashReader(r io.Reader) (string, error) {
+	h := md5.New()
+	if _, err := io.Copy(h, r); err != nil {
 		return "", err
 	}
-	defer f.Close()
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
 
-	h := md5.New()
-	buf := make([]byte, 1024)
-	for {
-		n, err := f.Read(buf)
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return "", err
+// verifyExecutorSignature validates the detached signature of the executor
+// at path against the trust anchor configured via gofig, if any is
+// configured. A client with no trust anchor configured skips verification
+// entirely, preserving today's unsigned behavior. This check is
+// independent of, and in addition to, the MD5 comparison against the
+// server-advertised checksum: it lets an operator pin executors to a
+// locally trusted Ed25519 signature or RSA PKCS#1 v1.5 signature over the
+// executor's SHA-256 digest without waiting on a server-side
+// digest upgrade. It runs on every path that ends in an executor being
+// trusted and used -- a fresh download, a checksum-already-matches local
+// file, and a content-cache hit alike -- not only on fresh downloads, so a
+// configured trust anchor is actually enforced for an executor already on
+// disk rather than grandfathering it in on MD5 alone.
+func (c *client) verifyExecutorSignature(
+	ctx types.Context, lsxPath string) error {
+
+	sigPath := c.config.GetString(configLSXSignaturePath)
+	keyPath := c.config.GetString(configLSXTrustAnchor)
+	if sigPath == "" || keyPath == "" {
+		ctx.Debug("no executor trust anchor configured, skipping signature verification")
+		return nil
+	}
+
+	lsx, err := ioutil.ReadFile(lsxPath)
+	if err != nil {
+		return err
+	}
+	sig, err := ioutil.ReadFile(sigPath)
+	if err != nil {
+		return err
+	}
+	keyPEM, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return err
+	}
+
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return goof.WithField("path", keyPath, "invalid trust anchor PEM")
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return goof.WithFieldE("path", keyPath, "invalid trust anchor key", err)
+	}
+
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, lsx, sig) {
+			return goof.WithField("lsx", lsxPath, "executor signature verification failed")
 		}
-		if _, err := h.Write(buf[:n]); err != nil {
-			return "", err
+	case *rsa.PublicKey:
+		sum := sha256.Sum256(lsx)
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, sum[:], sig); err != nil {
+			return goof.WithFieldE(
+				"lsx", lsxPath, "executor signature verification failed", err)
 		}
+	default:
+		return goof.WithField("path", keyPath, "unsupported trust anchor key type")
 	}
 
-	sum := fmt.Sprintf("%x", h.Sum(nil))
-	ctx.WithField("localChecksum", sum).Debug("got local executor checksum")
-	return sum, nil
+	ctx.Debug("executor signature verified")
+	return nil
 }
 
+// downloadExecutor fetches the executor in a single pass and does not
+// negotiate transport compression or resume a partial download by Range.
+// chunk0-3 asked for both, but the real vendored
+// types.APIClient.ExecutorGet(ctx, name) has no Accept-Encoding- or
+// Range-equivalent parameter to negotiate either over, and no server-side
+// handler in this tree to honor them -- the prior chunk0-1 rescoping
+// (8e2054f) removed the gzip/Range code this function had grown in 7fa1f82
+// for that reason. chunk0-3 is therefore not delivered: every download is a
+// full, uncompressed transfer from offset zero.
 func (c *client) downloadExecutor(ctx types.Context) error {
 
 	if c.isController() {
@@ -211,30 +566,150 @@ func (c *client) downloadExecutor(ctx types.Context) error {
 
 	ctx.Debug("downloading executor")
 
-	f, err := os.OpenFile(
-		c.pathConfig.LSX,
-		os.O_CREATE|os.O_RDWR|os.O_TRUNC,
-		0755)
-	if err != nil {
-		return err
+	name := path.Base(c.pathConfig.LSX)
+	lsxi := c.lsxCache.GetExecutorInfo(name)
+
+	var checksum string
+	if lsxi != nil {
+		checksum = executorChecksum(lsxi)
+	}
+
+	targetPath := c.pathConfig.LSX
+	cached := c.lsxCacheDir() != "" && checksum != ""
+	if cached {
+		if err := os.MkdirAll(c.lsxCacheEntryDir(checksum), 0755); err != nil {
+			return err
+		}
+		targetPath = c.lsxCacheEntryPath(checksum)
 	}
 
-	defer f.Close()
+	partPath := targetPath + ".part"
+	os.Remove(partPath)
 
-	rdr, err := c.APIClient.ExecutorGet(ctx, path.Base(c.pathConfig.LSX))
+	f, err := os.OpenFile(partPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
 	if err != nil {
 		return err
 	}
 
-	n, err := io.Copy(f, rdr)
+	rdr, err := c.APIClient.ExecutorGet(ctx, name)
 	if err != nil {
+		f.Close()
+		return err
+	}
+	defer rdr.Close()
+
+	total := int64(-1)
+	if lsxi != nil && lsxi.Size > 0 {
+		total = lsxi.Size
+	}
+	reporter := progressReporterFromContext(ctx, name)
+	reporter.Start(total)
+
+	h := md5.New()
+	n, copyErr := copyWithProgress(ctx, f, io.TeeReader(rdr, h), reporter, rdr)
+	if syncErr := f.Sync(); copyErr == nil {
+		copyErr = syncErr
+	}
+	f.Close()
+	reporter.Done(copyErr)
+
+	if copyErr != nil {
+		os.Remove(partPath)
+		ctx.WithField("bytes", n).WithError(copyErr).Warn(
+			"error downloading executor")
+		return copyErr
+	}
+
+	sum := fmt.Sprintf("%x", h.Sum(nil))
+	ctx.WithFields(log.Fields{
+		"bytes":    n,
+		"checksum": sum,
+	}).Debug("downloaded executor")
+
+	if checksum != "" && checksum != sum {
+		os.Remove(partPath)
+		return goof.WithFields(log.Fields{
+			"remoteChecksum": checksum,
+			"localChecksum":  sum,
+		}, "downloaded executor checksum does not match")
+	}
+
+	if err := c.verifyExecutorSignature(ctx, partPath); err != nil {
+		os.Remove(partPath)
 		return err
 	}
 
-	if err := f.Sync(); err != nil {
+	if err := os.Rename(partPath, targetPath); err != nil {
 		return err
 	}
 
-	ctx.WithField("bytes", n).Debug("downloaded executor")
+	// When cached, the caller (updateExecutorFromCache) is responsible for
+	// linking PathConfig.LSX to targetPath.
 	return nil
 }
+
+// progressReporterFromContext returns the ProgressReporter attached to
+// ctx, if the caller provided one via WithValue(progressReporterKey, ...),
+// falling back to the process default (a terminal progress bar or, for
+// non-TTY contexts, structured log lines).
+func progressReporterFromContext(
+	ctx types.Context, label string) ProgressReporter {
+
+	if pr, ok := ctx.Value(progressReporterKey).(ProgressReporter); ok && pr != nil {
+		return pr
+	}
+	return newProgressReporter(label)
+}
+
+// copyWithProgress copies src to dst, advancing reporter as bytes are read
+// and aborting early if ctx is canceled. On cancellation, closer is closed
+// to unblock the in-flight read so the copy can return promptly; whatever
+// was already written to dst is left in place, and removing it is up to
+// the caller (downloadExecutor deletes its .part file). This helper is not specific to executor downloads and is meant
+// to be reused by other long-running client operations that poll or stream
+// over a types.Context, such as volume attach/detach task polling.
+func copyWithProgress(
+	ctx types.Context,
+	dst io.Writer,
+	src io.Reader,
+	reporter ProgressReporter,
+	closer io.Closer) (int64, error) {
+
+	type result struct {
+		n   int64
+		err error
+	}
+	done := make(chan result, 1)
+
+	go func() {
+		n, err := io.Copy(dst, &reportingReader{r: src, reporter: reporter})
+		done <- result{n, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.n, r.err
+	case <-ctx.Done():
+		closer.Close()
+		r := <-done
+		if r.err == nil {
+			r.err = ctx.Err()
+		}
+		return r.n, r.err
+	}
+}
+
+// reportingReader wraps an io.Reader, advancing reporter by the number of
+// bytes read as they pass through.
+type reportingReader struct {
+	r        io.Reader
+	reporter ProgressReporter
+}
+
+func (r *reportingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.reporter.Advance(int64(n))
+	}
+	return n, err
+}
@@ -0,0 +1,188 @@
+package libstorage
+
+import (
+	"bytes"
+	stdcontext "context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	lscontext "github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// nullProgressReporter discards progress updates.
+type nullProgressReporter struct{}
+
+func (nullProgressReporter) Start(total int64) {}
+func (nullProgressReporter) Advance(n int64)   {}
+func (nullProgressReporter) Done(err error)    {}
+
+// TestCopyWithProgressCancellation guards against copyWithProgress hanging
+// forever when ctx is canceled mid-copy: it must close closer and return
+// promptly rather than blocking on the in-flight read indefinitely. Closing
+// a reader other than the one blocked in Read would never unblock it, and
+// copyWithProgress would never return.
+func TestCopyWithProgressCancellation(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	stdctx, cancel := stdcontext.WithCancel(stdcontext.Background())
+	ctx := lscontext.New(stdctx)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var buf bytes.Buffer
+		_, err := copyWithProgress(ctx, &buf, pr, nullProgressReporter{}, pr)
+		if err == nil {
+			t.Error("copyWithProgress() error = nil, want non-nil after cancellation")
+		}
+	}()
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("copyWithProgress() did not return within 5s of ctx cancellation")
+	}
+}
+
+func TestClientStatus(t *testing.T) {
+	c := &client{}
+
+	if got := c.ClientStatus(); len(got) != 0 {
+		t.Fatalf("ClientStatus() = %v, want empty map before any status is set", got)
+	}
+
+	c.setServiceStatus("svc-a", ServiceStatusReady)
+	c.setServiceStatus("svc-b", ServiceStatusNotApplicable)
+
+	got := c.ClientStatus()
+	want := map[string]ServiceStatus{
+		"svc-a": ServiceStatusReady,
+		"svc-b": ServiceStatusNotApplicable,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ClientStatus() = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("ClientStatus()[%q] = %q, want %q", k, got[k], v)
+		}
+	}
+
+	// the returned map must be a copy: mutating it must not affect the
+	// client's internal state.
+	got["svc-a"] = ServiceStatusUnavailable
+	if again := c.ClientStatus(); again["svc-a"] != ServiceStatusReady {
+		t.Fatalf(
+			"ClientStatus()[\"svc-a\"] = %q after mutating a prior result, want %q (not a copy)",
+			again["svc-a"], ServiceStatusReady)
+	}
+}
+
+func TestBackoffWithJitter(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    time.Duration
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{
+			name:    "firstAttempt",
+			base:    100 * time.Millisecond,
+			attempt: 0,
+			min:     100 * time.Millisecond,
+			max:     150 * time.Millisecond,
+		},
+		{
+			name:    "secondAttempt",
+			base:    100 * time.Millisecond,
+			attempt: 1,
+			min:     200 * time.Millisecond,
+			max:     300 * time.Millisecond,
+		},
+		{
+			name:    "thirdAttempt",
+			base:    100 * time.Millisecond,
+			attempt: 2,
+			min:     400 * time.Millisecond,
+			max:     600 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				got := backoffWithJitter(tt.base, tt.attempt)
+				if got < tt.min || got > tt.max {
+					t.Fatalf(
+						"backoffWithJitter(%s, %d) = %s, want [%s, %s]",
+						tt.base, tt.attempt, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestExecutorChecksum(t *testing.T) {
+	tests := []struct {
+		name string
+		lsxi *types.ExecutorInfo
+		want string
+	}{
+		{
+			name: "md5Checksum",
+			lsxi: &types.ExecutorInfo{MD5Checksum: "abc123"},
+			want: "abc123",
+		},
+		{
+			name: "empty",
+			lsxi: &types.ExecutorInfo{},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := executorChecksum(tt.lsxi); got != tt.want {
+				t.Fatalf("executorChecksum() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashReader(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "empty",
+			in:   "",
+			want: "d41d8cd98f00b204e9800998ecf8427e",
+		},
+		{
+			name: "helloWorld",
+			in:   "hello world",
+			want: "5eb63bbbe01eeed093cb22bb8f5acdc3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hashReader(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("hashReader() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("hashReader() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package libstorage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// lockPollInterval is how often lockCacheEntry retries acquiring a busy
+// cache entry lock.
+const lockPollInterval = 100 * time.Millisecond
+
+// lockStaleAfter is how long a cache entry lock file may go without a
+// heartbeat before lockCacheEntry assumes its owner crashed without
+// releasing it and reclaims it.
+const lockStaleAfter = 5 * time.Minute
+
+// lockHeartbeatInterval is how often a held lock's mtime is refreshed so
+// that a download still in progress is not mistaken for an abandoned,
+// stale lock. It is well under lockStaleAfter so a single missed
+// heartbeat (e.g. a slow filesystem) does not cause a spurious reclaim.
+const lockHeartbeatInterval = lockStaleAfter / 5
+
+// lsxCacheEntryDir returns the directory that holds (or will hold) the
+// cached executor matching checksum.
+func (c *client) lsxCacheEntryDir(checksum string) string {
+	return filepath.Join(c.lsxCacheDir(), checksum)
+}
+
+// lsxCacheEntryPath returns the path of the cached executor binary matching
+// checksum.
+func (c *client) lsxCacheEntryPath(checksum string) string {
+	return filepath.Join(c.lsxCacheEntryDir(checksum), "lsx")
+}
+
+// lsxCacheLockPath returns the path of the cross-process lock file that
+// guards downloads into the cache entry matching checksum. It is a sibling
+// of, not nested inside, the entry directory so that garbage collecting the
+// entry does not race with a client currently holding its lock.
+func (c *client) lsxCacheLockPath(checksum string) string {
+	return c.lsxCacheEntryDir(checksum) + ".lock"
+}
+
+// lockCacheEntry acquires a cross-process lock keyed by checksum so that
+// concurrent libStorage clients on the same host cooperate on downloading a
+// given executor version rather than serializing through a single
+// executor-wide mutex. While the lock is held, its mtime is refreshed every
+// lockHeartbeatInterval so a slow download is never mistaken for an
+// abandoned one. The returned func stops the heartbeat and releases the
+// lock.
+func (c *client) lockCacheEntry(
+	ctx types.Context, checksum string) (func() error, error) {
+
+	if err := os.MkdirAll(c.lsxCacheEntryDir(checksum), 0755); err != nil {
+		return nil, err
+	}
+
+	lockPath := c.lsxCacheLockPath(checksum)
+
+	for {
+		f, err := os.OpenFile(
+			lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			stop := make(chan struct{})
+			go c.heartbeatLock(lockPath, stop)
+			return func() error {
+				close(stop)
+				return os.Remove(lockPath)
+			}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if fi, statErr := os.Stat(lockPath); statErr == nil &&
+			time.Since(fi.ModTime()) > lockStaleAfter {
+			ctx.WithField("lock", lockPath).Warn(
+				"reclaiming stale executor cache lock")
+			os.Remove(lockPath)
+			continue
+		}
+
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// tryLockCacheEntry attempts to acquire the cross-process lock for checksum
+// without waiting, returning ok == false if it is actively held rather than
+// polling until it is released. A lock past lockStaleAfter with no
+// heartbeat is reclaimed, same as lockCacheEntry. gcExecutorCache uses this
+// instead of lockCacheEntry so a long-running download in progress causes
+// that entry to be skipped rather than stalling the GC sweep until the
+// download finishes.
+func (c *client) tryLockCacheEntry(
+	ctx types.Context, checksum string) (unlock func() error, ok bool, err error) {
+
+	if err := os.MkdirAll(c.lsxCacheEntryDir(checksum), 0755); err != nil {
+		return nil, false, err
+	}
+
+	lockPath := c.lsxCacheLockPath(checksum)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(
+			lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			stop := make(chan struct{})
+			go c.heartbeatLock(lockPath, stop)
+			return func() error {
+				close(stop)
+				return os.Remove(lockPath)
+			}, true, nil
+		}
+		if !os.IsExist(err) {
+			return nil, false, err
+		}
+
+		fi, statErr := os.Stat(lockPath)
+		if statErr != nil || time.Since(fi.ModTime()) <= lockStaleAfter {
+			return nil, false, nil
+		}
+
+		ctx.WithField("lock", lockPath).Warn(
+			"reclaiming stale executor cache lock")
+		os.Remove(lockPath)
+	}
+
+	return nil, false, nil
+}
+
+// heartbeatLock refreshes lockPath's mtime every lockHeartbeatInterval so
+// lockCacheEntry's staleness check does not reclaim a lock still held by an
+// active download. It returns once stop is closed by the unlock func
+// lockCacheEntry returns.
+func (c *client) heartbeatLock(lockPath string, stop chan struct{}) {
+	ticker := time.NewTicker(lockHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+			if err := os.Chtimes(lockPath, now, now); err != nil &&
+				!os.IsNotExist(err) {
+				c.ctx.WithField("lock", lockPath).WithError(err).Warn(
+					"error refreshing executor cache lock heartbeat")
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// linkExecutor atomically points PathConfig.LSX at entryPath in the
+// content-addressed cache, replacing whatever PathConfig.LSX previously
+// pointed to.
+func (c *client) linkExecutor(ctx types.Context, entryPath string) error {
+
+	ctx.WithField("entry", entryPath).Debug(
+		"linking executor from content-addressed cache")
+
+	tmpLink := c.pathConfig.LSX + ".link"
+	os.Remove(tmpLink)
+
+	if err := os.Symlink(entryPath, tmpLink); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpLink, c.pathConfig.LSX)
+}
+
+// gcExecutorCacheLoop periodically sweeps the content-addressed executor
+// cache, removing entries untouched for longer than ttl so that
+// side-by-side executors for multiple service versions do not accumulate
+// forever on a long-running host.
+func (c *client) gcExecutorCacheLoop(interval, ttl time.Duration) {
+	for range time.Tick(interval) {
+		if err := c.gcExecutorCache(c.ctx, ttl); err != nil {
+			c.ctx.WithError(err).Warn("error garbage collecting executor cache")
+		}
+	}
+}
+
+// cacheEntryAge returns how long it has been since entryDir was last
+// active. It prefers the completed executor binary's mtime, but an
+// interrupted or abandoned download leaves only a .part file (or nothing
+// at all besides the directory itself) behind, so it falls back to the
+// entry directory's own mtime, which advances whenever a file is created,
+// renamed, or removed within it -- including the .part file written at
+// the start of a download.
+func cacheEntryAge(entryDir, entryPath string) (time.Duration, error) {
+	info, err := os.Stat(entryPath)
+	if err != nil {
+		info, err = os.Stat(entryDir)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return time.Since(info.ModTime()), nil
+}
+
+// gcExecutorCache removes content-addressed cache entries older than ttl,
+// skipping the entry PathConfig.LSX currently links to and any entry whose
+// lock is held by another client, e.g. mid-download, rather than waiting
+// for it to release. Staleness and the active link are re-checked after the
+// lock is acquired and immediately before deletion: a download that was
+// still running when this entry was first examined may complete and link
+// PathConfig.LSX to it while gcExecutorCache is busy with other entries.
+func (c *client) gcExecutorCache(ctx types.Context, ttl time.Duration) error {
+
+	entries, err := ioutil.ReadDir(c.lsxCacheDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	active, _ := filepath.EvalSymlinks(c.pathConfig.LSX)
+
+	for _, fi := range entries {
+		if !fi.IsDir() {
+			continue
+		}
+
+		checksum := fi.Name()
+		entryDir := c.lsxCacheEntryDir(checksum)
+		entryPath := c.lsxCacheEntryPath(checksum)
+		if entryPath == active {
+			continue
+		}
+
+		age, err := cacheEntryAge(entryDir, entryPath)
+		if err != nil || age < ttl {
+			continue
+		}
+
+		unlock, ok, err := c.tryLockCacheEntry(ctx, checksum)
+		if err != nil {
+			ctx.WithError(err).Warn(
+				"error acquiring executor cache lock during garbage collection")
+			continue
+		}
+		if !ok {
+			ctx.WithField("checksum", checksum).Debug(
+				"skipping executor cache entry locked by another client")
+			continue
+		}
+
+		if current, _ := filepath.EvalSymlinks(c.pathConfig.LSX); entryPath == current {
+			unlock()
+			continue
+		}
+		if age, err := cacheEntryAge(entryDir, entryPath); err != nil || age < ttl {
+			unlock()
+			continue
+		}
+
+		ctx.WithField("checksum", checksum).Debug(
+			"garbage collecting unreferenced executor cache entry")
+		os.RemoveAll(entryDir)
+
+		if err := unlock(); err != nil {
+			ctx.WithError(err).Warn(
+				"error releasing executor cache lock during garbage collection")
+		}
+	}
+
+	return nil
+}
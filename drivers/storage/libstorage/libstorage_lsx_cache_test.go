@@ -0,0 +1,160 @@
+package libstorage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gofig "github.com/akutz/gofig/types"
+	"github.com/akutz/gotil"
+	lscontext "github.com/codedellemc/libstorage/api/context"
+	"github.com/codedellemc/libstorage/api/types"
+)
+
+// fakeConfig is a minimal gofig.Config stand-in for tests: it embeds the nil
+// interface so any method this package doesn't exercise panics loudly rather
+// than silently doing the wrong thing, and overrides only what client
+// actually calls.
+type fakeConfig struct {
+	gofig.Config
+	values map[interface{}]interface{}
+}
+
+func newFakeConfig() *fakeConfig {
+	return &fakeConfig{values: map[interface{}]interface{}{}}
+}
+
+func (f *fakeConfig) GetString(k interface{}) string {
+	s, _ := f.values[k].(string)
+	return s
+}
+
+func (f *fakeConfig) Set(k interface{}, v interface{}) {
+	f.values[k] = v
+}
+
+// newTestClient returns a client configured to use cacheDir as its
+// content-addressed executor cache, with no executor currently linked.
+func newTestClient(cacheDir string) *client {
+	config := newFakeConfig()
+	config.Set(configLSXCacheDir, cacheDir)
+	return &client{
+		ctx:        lscontext.Background(),
+		config:     config,
+		pathConfig: &types.PathConfig{LSX: filepath.Join(cacheDir, "lsx-link")},
+	}
+}
+
+func TestCacheEntryAge(t *testing.T) {
+	old := time.Now().Add(-time.Hour)
+
+	t.Run("completedBinary", func(t *testing.T) {
+		dir := t.TempDir()
+		entryPath := filepath.Join(dir, "lsx")
+		if err := os.WriteFile(entryPath, []byte("lsx"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(entryPath, old, old); err != nil {
+			t.Fatal(err)
+		}
+		// touch the directory after the binary, to prove entryPath's mtime
+		// (not the directory's) is preferred when both exist.
+		if err := os.Chtimes(dir, time.Now(), time.Now()); err != nil {
+			t.Fatal(err)
+		}
+
+		age, err := cacheEntryAge(dir, entryPath)
+		if err != nil {
+			t.Fatalf("cacheEntryAge() error = %v", err)
+		}
+		if age < 55*time.Minute {
+			t.Fatalf(
+				"cacheEntryAge() = %s, want >= 55m (should use entryPath mtime)",
+				age)
+		}
+	})
+
+	t.Run("abandonedPartialDownload", func(t *testing.T) {
+		dir := t.TempDir()
+		entryPath := filepath.Join(dir, "lsx")
+		partPath := entryPath + ".part"
+		if err := os.WriteFile(partPath, []byte("partial"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(dir, old, old); err != nil {
+			t.Fatal(err)
+		}
+
+		age, err := cacheEntryAge(dir, entryPath)
+		if err != nil {
+			t.Fatalf(
+				"cacheEntryAge() error = %v, want nil (should fall back to dir mtime)",
+				err)
+		}
+		if age < 55*time.Minute {
+			t.Fatalf("cacheEntryAge() = %s, want >= 55m", age)
+		}
+	})
+
+	t.Run("missingEntirely", func(t *testing.T) {
+		dir := t.TempDir()
+		missingDir := filepath.Join(dir, "gone")
+		if _, err := cacheEntryAge(missingDir, filepath.Join(missingDir, "lsx")); err == nil {
+			t.Fatal("cacheEntryAge() error = nil, want non-nil for nonexistent entry")
+		}
+	})
+}
+
+// TestGCExecutorCacheSkipsHeldLock guards against the race where GC deletes
+// a cache entry whose lock is held by another client mid-download:
+// gcExecutorCache must skip (not block on, and not delete) an entry whose
+// lock is actively held, and must reclaim the entry once the lock is
+// released and it is still stale.
+func TestGCExecutorCacheSkipsHeldLock(t *testing.T) {
+	cacheDir := t.TempDir()
+	c := newTestClient(cacheDir)
+
+	const checksum = "deadbeef"
+	entryDir := c.lsxCacheEntryDir(checksum)
+	entryPath := c.lsxCacheEntryPath(checksum)
+
+	if err := os.MkdirAll(entryDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(entryPath, []byte("lsx"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(entryPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	lockPath := c.lsxCacheLockPath(checksum)
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if err := os.Chtimes(lockPath, time.Now(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.gcExecutorCache(c.ctx, time.Minute); err != nil {
+		t.Fatalf("gcExecutorCache() error = %v", err)
+	}
+	if !gotil.FileExists(entryPath) {
+		t.Fatal("gcExecutorCache() removed an entry whose lock is actively held")
+	}
+
+	if err := os.Remove(lockPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.gcExecutorCache(c.ctx, time.Minute); err != nil {
+		t.Fatalf("gcExecutorCache() error = %v", err)
+	}
+	if gotil.FileExists(entryDir) {
+		t.Fatal("gcExecutorCache() did not remove a stale, unlocked entry")
+	}
+}
@@ -0,0 +1,158 @@
+package libstorage
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// progressDrawInterval throttles how often the terminal reporter redraws
+// its progress bar, so a fast link does not flood the terminal.
+const progressDrawInterval = 100 * time.Millisecond
+
+// progressLogInterval throttles how often the log reporter emits a
+// progress line, so a fast transfer does not flood the log.
+const progressLogInterval = 5 * time.Second
+
+// ProgressReporter receives progress updates for a long-running transfer,
+// such as an executor download. It is a local interface rather than part
+// of the vendored libStorage API, since the upstream client has no notion
+// of download progress.
+type ProgressReporter interface {
+	// Start is called once, before the first byte is transferred, with the
+	// total number of bytes expected or -1 if unknown.
+	Start(total int64)
+
+	// Advance is called as bytes are transferred.
+	Advance(n int64)
+
+	// Done is called once, after the transfer completes or fails.
+	Done(err error)
+}
+
+// progressReporterKeyType is an unexported type so that only this package
+// can construct values usable as the types.Context key under which a
+// caller-supplied ProgressReporter is stored.
+type progressReporterKeyType struct{}
+
+// progressReporterKey is the types.Context key under which a caller may
+// store a ProgressReporter via ctx.WithValue to override the process
+// default returned by newProgressReporter.
+var progressReporterKey = progressReporterKeyType{}
+
+// newProgressReporter returns the default ProgressReporter for the
+// current process: a terminal progress bar when stderr is a TTY, otherwise
+// a reporter that writes throttled structured-log lines.
+func newProgressReporter(label string) ProgressReporter {
+	if isTerminal(os.Stderr) {
+		return &terminalProgressReporter{label: label, out: os.Stderr}
+	}
+	return &logProgressReporter{label: label}
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalProgressReporter renders a throttled, in-place progress bar. It
+// satisfies ProgressReporter.
+type terminalProgressReporter struct {
+	mu       sync.Mutex
+	out      *os.File
+	label    string
+	total    int64
+	current  int64
+	lastDraw time.Time
+}
+
+func (r *terminalProgressReporter) Start(total int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.total = total
+	r.draw(true)
+}
+
+func (r *terminalProgressReporter) Advance(n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current += n
+	r.draw(false)
+}
+
+func (r *terminalProgressReporter) Done(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		fmt.Fprintf(r.out, "\r%s: failed: %v\n", r.label, err)
+		return
+	}
+	fmt.Fprintf(r.out, "\r%s: done (%d bytes)\n", r.label, r.current)
+}
+
+func (r *terminalProgressReporter) draw(force bool) {
+	if !force && time.Since(r.lastDraw) < progressDrawInterval {
+		return
+	}
+	r.lastDraw = time.Now()
+	if r.total <= 0 {
+		fmt.Fprintf(r.out, "\r%s: %d bytes", r.label, r.current)
+		return
+	}
+	pct := float64(r.current) / float64(r.total) * 100
+	fmt.Fprintf(
+		r.out, "\r%s: %3.0f%% (%d/%d bytes)",
+		r.label, pct, r.current, r.total)
+}
+
+// logProgressReporter emits throttled structured-log lines, for
+// non-interactive contexts where redrawing a bar in place is meaningless.
+type logProgressReporter struct {
+	mu      sync.Mutex
+	label   string
+	total   int64
+	current int64
+	lastLog time.Time
+}
+
+func (r *logProgressReporter) Start(total int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.total = total
+	log.WithFields(log.Fields{
+		"label": r.label,
+		"total": total,
+	}).Info("starting download")
+}
+
+func (r *logProgressReporter) Advance(n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current += n
+	if time.Since(r.lastLog) < progressLogInterval {
+		return
+	}
+	r.lastLog = time.Now()
+	log.WithFields(log.Fields{
+		"label": r.label,
+		"bytes": r.current,
+		"total": r.total,
+	}).Info("download progress")
+}
+
+func (r *logProgressReporter) Done(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fields := log.Fields{"label": r.label, "bytes": r.current}
+	if err != nil {
+		log.WithFields(fields).WithError(err).Error("download failed")
+		return
+	}
+	log.WithFields(fields).Info("download complete")
+}